@@ -0,0 +1,340 @@
+package sqlquery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Writer collects SQL fragments and args as a Cond is assembled, and
+// carries the Dialect to quote identifiers for.
+type Writer interface {
+	Write(sql string, args ...interface{}) error
+	Dialect() Dialect
+}
+
+type sqlWriter struct {
+	dialect Dialect
+	sql     strings.Builder
+	args    []interface{}
+}
+
+func newSQLWriter(d Dialect) *sqlWriter {
+	if d == nil {
+		d = MySQL
+	}
+	return &sqlWriter{dialect: d}
+}
+
+func (w *sqlWriter) Write(sql string, args ...interface{}) error {
+	w.sql.WriteString(sql)
+	w.args = append(w.args, args...)
+	return nil
+}
+
+func (w *sqlWriter) Dialect() Dialect { return w.dialect }
+
+// Cond is a composable SQL predicate, combined with And/Or/Not.
+type Cond interface {
+	WriteTo(w Writer) error
+	IsValid() bool
+}
+
+func condWriteField(w Writer, op string, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %s ?", w.Dialect().QuoteIdent(name), op))
+		args = append(args, fields[name])
+	}
+
+	return w.Write(strings.Join(parts, " AND "), args...)
+}
+
+// Eq renders "`field` = ?" for every key, ANDed together.
+type Eq map[string]interface{}
+
+func (e Eq) WriteTo(w Writer) error { return condWriteField(w, "=", e) }
+func (e Eq) IsValid() bool          { return len(e) > 0 }
+
+// Neq renders "`field` <> ?" for every key, ANDed together.
+type Neq map[string]interface{}
+
+func (n Neq) WriteTo(w Writer) error { return condWriteField(w, "<>", n) }
+func (n Neq) IsValid() bool          { return len(n) > 0 }
+
+// Gt renders "`field` > ?" for every key, ANDed together.
+type Gt map[string]interface{}
+
+func (g Gt) WriteTo(w Writer) error { return condWriteField(w, ">", g) }
+func (g Gt) IsValid() bool          { return len(g) > 0 }
+
+// Gte renders "`field` >= ?" for every key, ANDed together.
+type Gte map[string]interface{}
+
+func (g Gte) WriteTo(w Writer) error { return condWriteField(w, ">=", g) }
+func (g Gte) IsValid() bool          { return len(g) > 0 }
+
+// Lt renders "`field` < ?" for every key, ANDed together.
+type Lt map[string]interface{}
+
+func (l Lt) WriteTo(w Writer) error { return condWriteField(w, "<", l) }
+func (l Lt) IsValid() bool          { return len(l) > 0 }
+
+// Lte renders "`field` <= ?" for every key, ANDed together.
+type Lte map[string]interface{}
+
+func (l Lte) WriteTo(w Writer) error { return condWriteField(w, "<=", l) }
+func (l Lte) IsValid() bool          { return len(l) > 0 }
+
+// Like renders "`field` like ?" for every key, ANDed together.
+type Like map[string]interface{}
+
+func (l Like) WriteTo(w Writer) error { return condWriteField(w, "like", l) }
+func (l Like) IsValid() bool          { return len(l) > 0 }
+
+// In renders "`field` IN (?,?,...)".
+type In struct {
+	Field  string
+	Values []interface{}
+}
+
+func NewIn(field string, values ...interface{}) In {
+	return In{Field: field, Values: values}
+}
+
+func (i In) WriteTo(w Writer) error {
+	if len(i.Values) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(i.Values))
+	for idx := range i.Values {
+		placeholders[idx] = "?"
+	}
+
+	return w.Write(fmt.Sprintf("%s IN (%s)", w.Dialect().QuoteIdent(i.Field), strings.Join(placeholders, ",")), i.Values...)
+}
+
+func (i In) IsValid() bool { return len(i.Values) > 0 }
+
+// Expr renders a raw SQL fragment with its own args.
+type Expr struct {
+	SQL  string
+	Args []interface{}
+}
+
+func NewExpr(sql string, args ...interface{}) Expr {
+	return Expr{SQL: sql, Args: args}
+}
+
+func (e Expr) WriteTo(w Writer) error {
+	return w.Write(e.SQL, e.Args...)
+}
+
+func (e Expr) IsValid() bool { return e.SQL != "" }
+
+type condAnd []Cond
+
+// And combines conds with AND, parenthesizing any nested Or or Expr.
+func And(conds ...Cond) Cond {
+	return condAnd(validConds(conds))
+}
+
+func (c condAnd) WriteTo(w Writer) error {
+	return writeJoined(w, []Cond(c), " AND ")
+}
+
+func (c condAnd) IsValid() bool { return len(c) > 0 }
+
+type condOr []Cond
+
+// Or combines conds with OR, parenthesizing any nested And or Expr.
+func Or(conds ...Cond) Cond {
+	return condOr(validConds(conds))
+}
+
+func (c condOr) WriteTo(w Writer) error {
+	return writeJoined(w, []Cond(c), " OR ")
+}
+
+func (c condOr) IsValid() bool { return len(c) > 0 }
+
+type condNot struct {
+	cond Cond
+}
+
+// Not wraps cond in "NOT (...)". A nil or empty cond yields an invalid Not.
+func Not(cond Cond) Cond {
+	if cond == nil || !cond.IsValid() {
+		return condNot{}
+	}
+	return condNot{cond: cond}
+}
+
+func (c condNot) WriteTo(w Writer) error {
+	if !c.IsValid() {
+		return nil
+	}
+
+	inner := newSQLWriter(w.Dialect())
+	if err := c.cond.WriteTo(inner); err != nil {
+		return err
+	}
+	return w.Write(fmt.Sprintf("NOT (%s)", inner.sql.String()), inner.args...)
+}
+
+func (c condNot) IsValid() bool { return c.cond != nil }
+
+func validConds(conds []Cond) []Cond {
+	out := make([]Cond, 0, len(conds))
+	for _, c := range conds {
+		if c != nil && c.IsValid() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func writeJoined(w Writer, conds []Cond, sep string) error {
+	parts := make([]string, 0, len(conds))
+	var args []interface{}
+
+	for _, c := range conds {
+		inner := newSQLWriter(w.Dialect())
+		if err := c.WriteTo(inner); err != nil {
+			return err
+		}
+
+		sql := inner.sql.String()
+		if needsGrouping(c) {
+			sql = fmt.Sprintf("(%s)", sql)
+		}
+		parts = append(parts, sql)
+		args = append(args, inner.args...)
+	}
+
+	return w.Write(strings.Join(parts, sep), args...)
+}
+
+// needsGrouping reports whether c must be parenthesized when nested inside
+// an And or Or, to preserve precedence: a multi-part And or Or, or a raw
+// Expr, is always grouped.
+func needsGrouping(c Cond) bool {
+	switch v := c.(type) {
+	case condAnd:
+		return len(v) > 1
+	case condOr:
+		return len(v) > 1
+	case Expr:
+		return true
+	default:
+		return false
+	}
+}
+
+// Builder assembles a SELECT statement from composable Conds.
+type Builder struct {
+	table    string
+	selects  []string
+	where    Cond
+	orderBy  []string
+	limit    int
+	offset   int
+	hasLimit bool
+}
+
+func Select(columns ...string) *Builder {
+	return &Builder{selects: columns}
+}
+
+func (b *Builder) From(table string) *Builder {
+	b.table = table
+	return b
+}
+
+func (b *Builder) Where(cond Cond) *Builder {
+	b.where = cond
+	return b
+}
+
+func (b *Builder) OrderBy(columns ...string) *Builder {
+	b.orderBy = append(b.orderBy, columns...)
+	return b
+}
+
+func (b *Builder) Limit(limit int, offset int) *Builder {
+	b.limit = limit
+	b.offset = offset
+	b.hasLimit = true
+	return b
+}
+
+// Build renders the statement and its positional args, quoting the table
+// name and the Where identifiers for d (MySQL if d is nil). Select columns
+// and ORDER BY terms are written as given, unquoted. Run the result through
+// Queryable.Rebind before executing.
+func (b *Builder) Build(d Dialect) (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("sqlquery: Builder.From was never called")
+	}
+	if d == nil {
+		d = MySQL
+	}
+
+	columns := "*"
+	if len(b.selects) > 0 {
+		columns = strings.Join(b.selects, ", ")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	fmt.Fprintf(&sb, "SELECT %s FROM %s", columns, d.QuoteIdent(b.table))
+
+	if b.where != nil && b.where.IsValid() {
+		w := newSQLWriter(d)
+		if err := b.where.WriteTo(w); err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(w.sql.String())
+		args = append(args, w.args...)
+	}
+
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+
+	if b.hasLimit {
+		fmt.Fprintf(&sb, " LIMIT %d OFFSET %d", b.limit, b.offset)
+	}
+
+	return sb.String(), args, nil
+}
+
+// BuildQuery builds b for db's dialect and rebinds it for q.
+func (o *SqlQueryHandler) BuildQuery(db *Database, q Queryable, b *Builder) (string, []interface{}) {
+	if o.Error() {
+		return "", nil
+	}
+
+	query, args, err := b.Build(dialectOrDefault(db.Dialect))
+	if err != nil {
+		o.Set(err)
+		return "", nil
+	}
+
+	return q.Rebind(query), args
+}
@@ -0,0 +1,68 @@
+package sqlquery
+
+import "testing"
+
+func TestCondWriteToQuotesPerDialect(t *testing.T) {
+	cases := []struct {
+		name string
+		d    Dialect
+		want string
+	}{
+		{"mysql", MySQL, "`x` = ?"},
+		{"postgres", Postgres, "\"x\" = ?"},
+		{"mssql", MSSQL, "[x] = ?"},
+		{"sqlite", SQLite, "\"x\" = ?"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newSQLWriter(tc.d)
+			if err := (Eq{"x": 1}).WriteTo(w); err != nil {
+				t.Fatalf("WriteTo: %v", err)
+			}
+			if got := w.sql.String(); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInWriteToQuotesPerDialect(t *testing.T) {
+	w := newSQLWriter(Postgres)
+	if err := NewIn("y", 1, 2).WriteTo(w); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if got, want := w.sql.String(), `"y" IN (?,?)`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAndGroupsNestedExpr(t *testing.T) {
+	// A raw Expr may itself contain a top-level OR, so it must always be
+	// parenthesized when nested inside And/Or or it silently changes the
+	// query's operator precedence.
+	cond := And(Eq{"x": 1}, NewExpr("a=1 OR b=2"))
+
+	w := newSQLWriter(MySQL)
+	if err := cond.WriteTo(w); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if got, want := w.sql.String(), "`x` = ? AND (a=1 OR b=2)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBuildQuotesTableAndWherePerDialect(t *testing.T) {
+	b := Select("id").From("users").Where(Eq{"name": "bob"})
+
+	query, args, err := b.Build(MSSQL)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got, want := query, "SELECT id FROM [users] WHERE [name] = ?"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(args) != 1 || args[0] != "bob" {
+		t.Errorf("got args %v, want [bob]", args)
+	}
+}
@@ -0,0 +1,245 @@
+package sqlquery
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// crudMapper derives column names from the `db` struct tag sqlx itself uses.
+var crudMapper = reflectx.NewMapper("db")
+
+// crudMeta is what Insert/Update/Delete/Get derive from a struct's `db` and
+// `sqlquery` tags.
+type crudMeta struct {
+	table         string
+	columns       []string
+	fieldIndex    map[string]int
+	pkColumn      string
+	versionColumn string
+}
+
+// parseCrudMeta reads v's struct tags:
+//
+//	db:"col_name"                     column name (skip the field if absent or "-")
+//	sqlquery:"table=name"             table name, required on exactly one field
+//	sqlquery:"pk"                     marks the primary key column
+//	sqlquery:"version"                marks the optimistic-concurrency version column
+//
+// Multiple sqlquery options are comma-separated, e.g. `sqlquery:"table=users,pk"`.
+func parseCrudMeta(v interface{}) (*crudMeta, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlquery: %T is not a struct", v)
+	}
+	rt := rv.Type()
+
+	meta := &crudMeta{fieldIndex: make(map[string]int)}
+
+	for _, fi := range crudMapper.TypeMap(rt).Index {
+		// skip embedded/nested fields
+		if len(fi.Index) != 1 {
+			continue
+		}
+
+		dbTag, ok := fi.Field.Tag.Lookup("db")
+		if !ok || dbTag == "-" {
+			continue
+		}
+
+		opts := parseTagOptions(fi.Field.Tag.Get("sqlquery"))
+		if table, ok := opts["table"]; ok {
+			meta.table = table
+		}
+		if _, ok := opts["pk"]; ok {
+			meta.pkColumn = fi.Name
+		}
+		if _, ok := opts["version"]; ok {
+			meta.versionColumn = fi.Name
+		}
+
+		meta.columns = append(meta.columns, fi.Name)
+		meta.fieldIndex[fi.Name] = fi.Index[0]
+	}
+
+	if meta.table == "" {
+		return nil, fmt.Errorf("sqlquery: %s has no field tagged `sqlquery:\"table=...\"`", rt.Name())
+	}
+	if meta.pkColumn == "" {
+		return nil, fmt.Errorf("sqlquery: %s has no field tagged `sqlquery:\"pk\"`", rt.Name())
+	}
+
+	return meta, nil
+}
+
+func parseTagOptions(tag string) map[string]string {
+	opts := make(map[string]string)
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			opts[kv[0]] = kv[1]
+		} else {
+			opts[part] = ""
+		}
+	}
+	return opts
+}
+
+// isInvalidNull reports whether fv is a sql.Null* value whose Valid is false.
+func isInvalidNull(fv interface{}) bool {
+	rv := reflect.ValueOf(fv)
+	if rv.Kind() != reflect.Struct {
+		return false
+	}
+
+	valid := rv.FieldByName("Valid")
+	if !valid.IsValid() || valid.Kind() != reflect.Bool {
+		return false
+	}
+	return !valid.Bool()
+}
+
+// Insert builds and runs an INSERT for v, deriving table and columns from
+// its struct tags (see parseCrudMeta). Columns holding an invalid
+// sql.Null* value are omitted so the database applies their default.
+func (o *SqlQueryHandler) Insert(ctx context.Context, db *Database, tx *sqlx.Tx, v interface{}) {
+	if o.Error() {
+		return
+	}
+
+	meta, err := parseCrudMeta(v)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	d := dialectOrDefault(db.Dialect)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	var cols []string
+	var placeholders []string
+	var args []interface{}
+
+	for _, col := range meta.columns {
+		fv := rv.Field(meta.fieldIndex[col]).Interface()
+		if isInvalidNull(fv) {
+			continue
+		}
+		cols = append(cols, d.QuoteIdent(col))
+		placeholders = append(placeholders, d.Placeholder(len(args)+1))
+		args = append(args, fv)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		d.QuoteIdent(meta.table), strings.Join(cols, ","), strings.Join(placeholders, ","))
+	o.ExecContext(ctx, db, tx, query, args...)
+}
+
+// Update builds and runs an UPDATE for v, matching on its primary key. If
+// v's struct has a `sqlquery:"version"` column, the WHERE clause also
+// requires the in-memory version to match the stored one, and a zero-row
+// update is reported as an optimistic concurrency conflict.
+func (o *SqlQueryHandler) Update(ctx context.Context, db *Database, tx *sqlx.Tx, v interface{}) {
+	if o.Error() {
+		return
+	}
+
+	meta, err := parseCrudMeta(v)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	d := dialectOrDefault(db.Dialect)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	var sets []string
+	var args []interface{}
+
+	for _, col := range meta.columns {
+		if col == meta.pkColumn || col == meta.versionColumn {
+			continue
+		}
+		args = append(args, rv.Field(meta.fieldIndex[col]).Interface())
+		sets = append(sets, fmt.Sprintf("%s = %s", d.QuoteIdent(col), d.Placeholder(len(args))))
+	}
+
+	if meta.versionColumn != "" {
+		sets = append(sets, fmt.Sprintf("%s = %s + 1", d.QuoteIdent(meta.versionColumn), d.QuoteIdent(meta.versionColumn)))
+	}
+
+	args = append(args, rv.Field(meta.fieldIndex[meta.pkColumn]).Interface())
+	where := fmt.Sprintf("%s = %s", d.QuoteIdent(meta.pkColumn), d.Placeholder(len(args)))
+
+	if meta.versionColumn != "" {
+		args = append(args, rv.Field(meta.fieldIndex[meta.versionColumn]).Interface())
+		where += fmt.Sprintf(" AND %s = %s", d.QuoteIdent(meta.versionColumn), d.Placeholder(len(args)))
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", d.QuoteIdent(meta.table), strings.Join(sets, ", "), where)
+	result := o.ExecContext(ctx, db, tx, query, args...)
+	if o.Error() || meta.versionColumn == "" || result == nil {
+		return
+	}
+
+	if n, rowsErr := result.RowsAffected(); rowsErr == nil && n == 0 {
+		o.Set(fmt.Errorf("sqlquery: optimistic concurrency conflict updating %s", meta.table))
+	}
+}
+
+// Delete removes the row matching v's primary key.
+func (o *SqlQueryHandler) Delete(ctx context.Context, db *Database, tx *sqlx.Tx, v interface{}) {
+	if o.Error() {
+		return
+	}
+
+	meta, err := parseCrudMeta(v)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	d := dialectOrDefault(db.Dialect)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = %s", d.QuoteIdent(meta.table), d.QuoteIdent(meta.pkColumn), d.Placeholder(1))
+	o.ExecContext(ctx, db, tx, query, rv.Field(meta.fieldIndex[meta.pkColumn]).Interface())
+}
+
+// Get loads the row whose primary key is pk into dest, using dest's struct
+// tags to derive the table and primary key column.
+func (o *SqlQueryHandler) Get(ctx context.Context, db *Database, tx *sqlx.Tx, dest interface{}, pk interface{}) {
+	if o.Error() {
+		return
+	}
+
+	meta, err := parseCrudMeta(dest)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	d := dialectOrDefault(db.Dialect)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = %s", d.QuoteIdent(meta.table), d.QuoteIdent(meta.pkColumn), d.Placeholder(1))
+
+	stmt, err := db.preparedFor(ctx, tx, query)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	args := []interface{}{pk}
+	ctx = runHooksBefore(db.Hooks, ctx, query, args)
+	start := time.Now()
+	err = stmt.GetContext(ctx, dest, pk)
+	runHooksAfter(db.Hooks, ctx, query, args, err, start)
+	o.Set(err)
+}
@@ -0,0 +1,59 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+type widget struct {
+	ID      int            `db:"id" sqlquery:"table=widgets,pk"`
+	Name    string         `db:"name"`
+	Notes   sql.NullString `db:"notes"`
+	Version int            `db:"version" sqlquery:"version"`
+}
+
+func TestInsertSkipsInvalidNullColumnsWithoutGappingPlaceholders(t *testing.T) {
+	conn, rec := newFakeDB(t)
+	db := &Database{Conn: conn, Dialect: Postgres}
+
+	var err error
+	var o SqlQueryHandler
+	o.Init(&err)
+
+	o.Insert(context.Background(), db, nil, &widget{ID: 1, Name: "a"})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	query, args := rec.last()
+	if want := `INSERT INTO "widgets" ("id","name","version") VALUES ($1,$2,$3)`; query != want {
+		t.Errorf("got query %q, want %q", query, want)
+	}
+	if len(args) != 3 {
+		t.Errorf("got %d args, want 3 (notes should have been skipped, not left as a gap)", len(args))
+	}
+}
+
+func TestUpdateNumbersPlaceholdersAcrossSetAndVersionedWhere(t *testing.T) {
+	conn, rec := newFakeDB(t)
+	db := &Database{Conn: conn, Dialect: Postgres}
+
+	var err error
+	var o SqlQueryHandler
+	o.Init(&err)
+
+	o.Update(context.Background(), db, nil, &widget{ID: 1, Name: "a", Version: 2})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	query, args := rec.last()
+	want := `UPDATE "widgets" SET "name" = $1, "version" = "version" + 1 WHERE "id" = $2 AND "version" = $3`
+	if query != want {
+		t.Errorf("got query %q, want %q", query, want)
+	}
+	if len(args) != 3 {
+		t.Errorf("got %d args, want 3 (name, pk, version)", len(args))
+	}
+}
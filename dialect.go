@@ -0,0 +1,79 @@
+package sqlquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect isolates identifier quoting and placeholder syntax per backend.
+type Dialect interface {
+	Name() string
+	QuoteIdent(name string) string
+	Placeholder(n int) string
+	SupportsReturning() bool
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string                 { return "mysql" }
+func (mysqlDialect) QuoteIdent(name string) string { return fmt.Sprintf("`%s`", name) }
+func (mysqlDialect) Placeholder(n int) string      { return "?" }
+func (mysqlDialect) SupportsReturning() bool       { return false }
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string                 { return "postgres" }
+func (postgresDialect) QuoteIdent(name string) string { return fmt.Sprintf("%q", name) }
+func (postgresDialect) Placeholder(n int) string      { return fmt.Sprintf("$%d", n) }
+func (postgresDialect) SupportsReturning() bool       { return true }
+
+type mssqlDialect struct{}
+
+func (mssqlDialect) Name() string                 { return "mssql" }
+func (mssqlDialect) QuoteIdent(name string) string { return fmt.Sprintf("[%s]", name) }
+func (mssqlDialect) Placeholder(n int) string      { return fmt.Sprintf("@p%d", n) }
+func (mssqlDialect) SupportsReturning() bool       { return false }
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string                 { return "sqlite" }
+func (sqliteDialect) QuoteIdent(name string) string { return fmt.Sprintf("%q", name) }
+func (sqliteDialect) Placeholder(n int) string      { return "?" }
+func (sqliteDialect) SupportsReturning() bool       { return true }
+
+var (
+	MySQL    Dialect = mysqlDialect{}
+	Postgres Dialect = postgresDialect{}
+	MSSQL    Dialect = mssqlDialect{}
+	SQLite   Dialect = sqliteDialect{}
+)
+
+// dialectForDriver maps a driver name to its Dialect, defaulting to MySQL.
+func dialectForDriver(driverName string) Dialect {
+	switch strings.ToLower(driverName) {
+	case "postgres", "pgx", "pq":
+		return Postgres
+	case "sqlserver", "mssql":
+		return MSSQL
+	case "sqlite3", "sqlite":
+		return SQLite
+	default:
+		return MySQL
+	}
+}
+
+// dialectOrDefault returns d, or MySQL if d is nil.
+func dialectOrDefault(d Dialect) Dialect {
+	if d != nil {
+		return d
+	}
+	return MySQL
+}
+
+// quoteTableField renders "table"."field", or just "field" if table is empty.
+func quoteTableField(d Dialect, table, field string) string {
+	if table == "" {
+		return d.QuoteIdent(field)
+	}
+	return fmt.Sprintf("%s.%s", d.QuoteIdent(table), d.QuoteIdent(field))
+}
@@ -0,0 +1,115 @@
+package sqlquery
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeDriver is a minimal database/sql driver that never talks to a real
+// database. It exists so stmtCache/crud tests can exercise real
+// *sqlx.Stmt/*sqlx.Tx plumbing and assert on the SQL a code path actually
+// produced, via the callRecorder registered under the DSN it was opened
+// with.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{name: name}, nil }
+
+var registerFakeDriverOnce sync.Once
+
+type callRecorder struct {
+	mu    sync.Mutex
+	query string
+	args  []driver.Value
+}
+
+func (r *callRecorder) record(query string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.query, r.args = query, args
+}
+
+func (r *callRecorder) last() (string, []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.query, r.args
+}
+
+var fakeRecorders sync.Map // dsn string -> *callRecorder
+
+// newFakeDB returns a *sqlx.DB backed by fakeDriver, and the recorder that
+// captures the last statement it executed or queried.
+func newFakeDB(t *testing.T) (*sqlx.DB, *callRecorder) {
+	t.Helper()
+
+	registerFakeDriverOnce.Do(func() {
+		sql.Register("sqlquery-fake", fakeDriver{})
+	})
+
+	rec := &callRecorder{}
+	dsn := fmt.Sprintf("test-%p", rec)
+	fakeRecorders.Store(dsn, rec)
+	t.Cleanup(func() { fakeRecorders.Delete(dsn) })
+
+	db, err := sql.Open("sqlquery-fake", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "sqlquery-fake"), rec
+}
+
+type fakeConn struct {
+	name string
+}
+
+func (c *fakeConn) recorder() *callRecorder {
+	rec, ok := fakeRecorders.Load(c.name)
+	if !ok {
+		return &callRecorder{}
+	}
+	return rec.(*callRecorder)
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query, recorder: c.recorder()}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	query    string
+	recorder *callRecorder
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.recorder.record(s.query, args)
+	return driver.ResultNoRows, nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.recorder.record(s.query, args)
+	return &fakeRows{}, nil
+}
+
+// fakeRows always reports zero rows; nothing in this package's tests scans
+// query results, only the SQL/args a call produced.
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return nil }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
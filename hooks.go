@@ -0,0 +1,206 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Hook lets callers observe every query SqlQueryHandler runs.
+type Hook interface {
+	Before(ctx context.Context, query string, args []interface{}) context.Context
+	After(ctx context.Context, query string, args []interface{}, err error, duration time.Duration)
+}
+
+type rowsAffectedCtxKey struct{}
+
+// RowsAffectedFromContext returns the rows-affected count set on the ctx
+// passed to Hook.After, if the exec succeeded and the driver reports it.
+func RowsAffectedFromContext(ctx context.Context) (int64, bool) {
+	n, ok := ctx.Value(rowsAffectedCtxKey{}).(int64)
+	return n, ok
+}
+
+// RegisterHook adds h to db's hook chain.
+func (db *Database) RegisterHook(h Hook) {
+	db.Hooks = append(db.Hooks, h)
+}
+
+// Queryable returns tx (or db.Conn, if tx is nil) wrapped to fire db's Hooks.
+func (db *Database) Queryable(tx *sqlx.Tx) Queryable {
+	var inner Queryable
+	if tx != nil {
+		inner = tx
+	} else {
+		inner = db.Conn
+	}
+
+	if len(db.Hooks) == 0 {
+		return inner
+	}
+	return &hookedQueryable{inner: inner, hooks: db.Hooks}
+}
+
+// runHooksBefore and runHooksAfter are shared by hookedQueryable and the
+// stmt-cache path in SqlQueryHandler.
+func runHooksBefore(hooks []Hook, ctx context.Context, query string, args []interface{}) context.Context {
+	for _, hook := range hooks {
+		ctx = hook.Before(ctx, query, args)
+	}
+	return ctx
+}
+
+// runHooksAfter runs hooks in reverse registration order, like middleware.
+func runHooksAfter(hooks []Hook, ctx context.Context, query string, args []interface{}, err error, start time.Time) {
+	duration := time.Since(start)
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].After(ctx, query, args, err, duration)
+	}
+}
+
+type hookedQueryable struct {
+	inner Queryable
+	hooks []Hook
+}
+
+func (h *hookedQueryable) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	rows, err := h.inner.QueryContext(ctx, query, args...)
+	runHooksAfter(h.hooks, ctx, query, args, err, start)
+	return rows, err
+}
+
+func (h *hookedQueryable) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	rows, err := h.inner.QueryxContext(ctx, query, args...)
+	runHooksAfter(h.hooks, ctx, query, args, err, start)
+	return rows, err
+}
+
+func (h *hookedQueryable) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	result, err := h.inner.ExecContext(ctx, query, args...)
+	runHooksAfter(h.hooks, withRowsAffected(ctx, result, err), query, args, err, start)
+	return result, err
+}
+
+func (h *hookedQueryable) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	args := []interface{}{arg}
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	result, err := h.inner.NamedExecContext(ctx, query, arg)
+	runHooksAfter(h.hooks, withRowsAffected(ctx, result, err), query, args, err, start)
+	return result, err
+}
+
+func (h *hookedQueryable) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	err := h.inner.SelectContext(ctx, dest, query, args...)
+	runHooksAfter(h.hooks, ctx, query, args, err, start)
+	return err
+}
+
+func (h *hookedQueryable) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx = runHooksBefore(h.hooks, ctx, query, args)
+	start := time.Now()
+	err := h.inner.GetContext(ctx, dest, query, args...)
+	runHooksAfter(h.hooks, ctx, query, args, err, start)
+	return err
+}
+
+func (h *hookedQueryable) Rebind(query string) string {
+	return h.inner.Rebind(query)
+}
+
+func withRowsAffected(ctx context.Context, result sql.Result, err error) context.Context {
+	if err != nil || result == nil {
+		return ctx
+	}
+	if n, rerr := result.RowsAffected(); rerr == nil {
+		return context.WithValue(ctx, rowsAffectedCtxKey{}, n)
+	}
+	return ctx
+}
+
+// LoggingHook is a built-in Hook that logs every query via slog: Debug on
+// success, Error on failure.
+type LoggingHook struct {
+	Logger *slog.Logger
+}
+
+func (h LoggingHook) logger() *slog.Logger {
+	if h.Logger != nil {
+		return h.Logger
+	}
+	return slog.Default()
+}
+
+func (h LoggingHook) Before(ctx context.Context, query string, args []interface{}) context.Context {
+	return ctx
+}
+
+func (h LoggingHook) After(ctx context.Context, query string, args []interface{}, err error, duration time.Duration) {
+	if err != nil {
+		h.logger().ErrorContext(ctx, "sqlquery: query failed", "query", query, "args", args, "duration", duration, "error", err)
+		return
+	}
+	h.logger().DebugContext(ctx, "sqlquery: query", "query", query, "args", args, "duration", duration)
+}
+
+// TracingSpan is the subset of an OpenTelemetry span TracingHook needs,
+// so this package doesn't import go.opentelemetry.io directly.
+type TracingSpan interface {
+	SetAttributes(attrs ...TracingAttribute)
+	RecordError(err error)
+	End()
+}
+
+// TracingAttribute mirrors OpenTelemetry's attribute.KeyValue shape.
+type TracingAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+type tracingSpanCtxKey struct{}
+
+// TracingHook is a built-in, otelsql-style Hook.
+type TracingHook struct {
+	System string
+	Tracer func(ctx context.Context, spanName string) (context.Context, TracingSpan)
+}
+
+func (h TracingHook) Before(ctx context.Context, query string, args []interface{}) context.Context {
+	if h.Tracer == nil {
+		return ctx
+	}
+
+	spanCtx, span := h.Tracer(ctx, "sqlquery.query")
+	span.SetAttributes(
+		TracingAttribute{Key: "db.statement", Value: query},
+		TracingAttribute{Key: "db.system", Value: h.System},
+	)
+	return context.WithValue(spanCtx, tracingSpanCtxKey{}, span)
+}
+
+func (h TracingHook) After(ctx context.Context, query string, args []interface{}, err error, duration time.Duration) {
+	span, ok := ctx.Value(tracingSpanCtxKey{}).(TracingSpan)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	if n, ok := RowsAffectedFromContext(ctx); ok {
+		span.SetAttributes(TracingAttribute{Key: "db.rows_affected", Value: n})
+	}
+}
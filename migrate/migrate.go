@@ -0,0 +1,281 @@
+// Package migrate provides a small, cross-driver schema migration runner
+// built on top of sqlquery.Database and sqlquery.SqlQueryHandler.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	sqlquery "github.com/hawkwithwind/sql-query"
+)
+
+// Migration is a single, reversible schema change. Up and Down each run
+// inside their own transaction.
+type Migration struct {
+	ID   string
+	Up   func(tx *sqlx.Tx) error
+	Down func(tx *sqlx.Tx) error
+}
+
+// Status reports whether a registered Migration has been applied.
+type Status struct {
+	ID      string
+	Applied bool
+}
+
+// Migrator applies and reverts Migrations against db.
+type Migrator struct {
+	db         *sqlquery.Database
+	table      string
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlquery.Database) *Migrator {
+	return &Migrator{db: db, table: "schema_migrations"}
+}
+
+// Register adds migrations, keeping them sorted lexicographically by ID.
+func (m *Migrator) Register(migrations ...Migration) {
+	m.migrations = append(m.migrations, migrations...)
+	sort.Slice(m.migrations, func(i, j int) bool { return m.migrations[i].ID < m.migrations[j].ID })
+}
+
+func (m *Migrator) dialect() sqlquery.Dialect {
+	if m.db.Dialect != nil {
+		return m.db.Dialect
+	}
+	return sqlquery.MySQL
+}
+
+func (m *Migrator) quotedTable() string {
+	return m.dialect().QuoteIdent(m.table)
+}
+
+// timestampColumnType returns the DDL type for applied_at. MSSQL's
+// TIMESTAMP is a rowversion type, not a settable date/time value.
+func (m *Migrator) timestampColumnType() string {
+	if m.dialect().Name() == "mssql" {
+		return "DATETIME2"
+	}
+	return "TIMESTAMP"
+}
+
+// createTableDDL creates schema_migrations if it doesn't exist yet. MSSQL
+// has no CREATE TABLE IF NOT EXISTS, so it needs a sys.tables check instead.
+func (m *Migrator) createTableDDL() string {
+	body := fmt.Sprintf("id VARCHAR(255) PRIMARY KEY, applied_at %s NOT NULL", m.timestampColumnType())
+
+	if m.dialect().Name() == "mssql" {
+		return fmt.Sprintf(
+			"IF NOT EXISTS (SELECT 1 FROM sys.tables WHERE name = '%s') CREATE TABLE %s (%s)",
+			m.table, m.quotedTable(), body,
+		)
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", m.quotedTable(), body)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	var o sqlquery.SqlQueryHandler
+	var err error
+	o.Init(&err)
+
+	tx := o.Begin(m.db)
+	o.ExecContext(ctx, m.db, tx, m.createTableDDL())
+	o.CommitOrRollback(m.db, tx)
+	return err
+}
+
+func (m *Migrator) appliedIDs(ctx context.Context) (map[string]bool, error) {
+	var o sqlquery.SqlQueryHandler
+	var err error
+	o.Init(&err)
+
+	var ids []string
+	o.SelectContext(ctx, m.db, nil, &ids, fmt.Sprintf("SELECT id FROM %s", m.quotedTable()))
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		applied[id] = true
+	}
+	return applied, nil
+}
+
+// Status reports every registered migration and whether it's applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{ID: mig.ID, Applied: applied[mig.ID]}
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if applied[mig.ID] {
+			continue
+		}
+		if err := m.apply(ctx, mig, true); err != nil {
+			return fmt.Errorf("migrate: applying %s: %w", mig.ID, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if !applied[mig.ID] {
+			continue
+		}
+		if err := m.apply(ctx, mig, false); err != nil {
+			return fmt.Errorf("migrate: reverting %s: %w", mig.ID, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Redo reverts and then re-applies the most recently applied migration.
+func (m *Migrator) Redo(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !statuses[i].Applied {
+			continue
+		}
+		if err := m.apply(ctx, m.migrations[i], true); err != nil {
+			return fmt.Errorf("migrate: applying %s: %w", m.migrations[i].ID, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration, up bool) error {
+	var o sqlquery.SqlQueryHandler
+	var err error
+	o.Init(&err)
+
+	tx := o.Begin(m.db)
+	if err != nil {
+		return err
+	}
+
+	step := mig.Down
+	if up {
+		step = mig.Up
+	}
+
+	d := m.dialect()
+
+	if stepErr := step(tx); stepErr != nil {
+		o.Set(stepErr)
+	} else if up {
+		query := fmt.Sprintf("INSERT INTO %s (id, applied_at) VALUES (%s, %s)", m.quotedTable(), d.Placeholder(1), d.Placeholder(2))
+		o.ExecContext(ctx, m.db, tx, query, mig.ID, time.Now())
+	} else {
+		query := fmt.Sprintf("DELETE FROM %s WHERE id = %s", m.quotedTable(), d.Placeholder(1))
+		o.ExecContext(ctx, m.db, tx, query, mig.ID)
+	}
+
+	o.CommitOrRollback(m.db, tx)
+	return err
+}
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// ParseSQLMigration builds a Migration from a goose-style SQL file whose Up
+// and Down sections are separated by "-- +migrate Up" / "-- +migrate Down"
+// marker comments. Each section may hold multiple ';'-separated statements.
+func ParseSQLMigration(id string, content string) (Migration, error) {
+	up, down, err := splitSQLSections(content)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	return Migration{
+		ID:   id,
+		Up:   execSQLStep(up),
+		Down: execSQLStep(down),
+	}, nil
+}
+
+func splitSQLSections(content string) (up string, down string, err error) {
+	upIdx := strings.Index(content, upMarker)
+	if upIdx == -1 {
+		return "", "", fmt.Errorf("migrate: sql migration missing %q marker", upMarker)
+	}
+
+	downIdx := strings.Index(content, downMarker)
+	if downIdx == -1 {
+		return content[upIdx+len(upMarker):], "", nil
+	}
+	if downIdx < upIdx {
+		return "", "", fmt.Errorf("migrate: %q appears before %q", downMarker, upMarker)
+	}
+
+	return content[upIdx+len(upMarker) : downIdx], content[downIdx+len(downMarker):], nil
+}
+
+func execSQLStep(sqlText string) func(tx *sqlx.Tx) error {
+	statements := strings.Split(sqlText, ";")
+
+	return func(tx *sqlx.Tx) error {
+		for _, stmt := range statements {
+			stmt = strings.TrimSpace(stmt)
+			if stmt == "" {
+				continue
+			}
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	sqlquery "github.com/hawkwithwind/sql-query"
+)
+
+// fakeDriver is a minimal database/sql driver that never talks to a real
+// database: every Exec/Query succeeds and returns no rows. It exists so
+// Migrator's Register/Up/Down ordering can be tested against a real
+// sqlquery.Database without a live connection.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                    { return nil }
+func (fakeStmt) NumInput() int                                   { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.ResultNoRows, nil }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string              { return nil }
+func (fakeRows) Close() error                   { return nil }
+func (fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+var registerFakeDriverOnce sync.Once
+
+func newFakeDatabase(t *testing.T) *sqlquery.Database {
+	t.Helper()
+
+	registerFakeDriverOnce.Do(func() {
+		sql.Register("migrate-fake", fakeDriver{})
+	})
+
+	db, err := sql.Open("migrate-fake", "test")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &sqlquery.Database{Conn: sqlx.NewDb(db, "migrate-fake"), Dialect: sqlquery.MySQL}
+}
+
+func TestUpAppliesMigrationsInRegisteredOrder(t *testing.T) {
+	m := NewMigrator(newFakeDatabase(t))
+
+	var order []string
+	record := func(id string) func(tx *sqlx.Tx) error {
+		return func(tx *sqlx.Tx) error {
+			order = append(order, id)
+			return nil
+		}
+	}
+
+	// Registered out of order; Up must still apply them sorted by ID.
+	m.Register(
+		Migration{ID: "20260102_b", Up: record("20260102_b")},
+		Migration{ID: "20260101_a", Up: record("20260101_a")},
+		Migration{ID: "20260103_c", Up: record("20260103_c")},
+	)
+
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	want := []string{"20260101_a", "20260102_b", "20260103_c"}
+	if len(order) != len(want) {
+		t.Fatalf("got %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestMSSQLEnsureTableUsesSysTablesCheckInsteadOfIfNotExists(t *testing.T) {
+	db := newFakeDatabase(t)
+	db.Dialect = sqlquery.MSSQL
+
+	m := NewMigrator(db)
+	ddl := m.createTableDDL()
+
+	if !strings.Contains(ddl, "IF NOT EXISTS (SELECT 1 FROM sys.tables") {
+		t.Errorf("mssql DDL should check sys.tables, got %q", ddl)
+	}
+	if strings.Contains(ddl, "CREATE TABLE IF NOT EXISTS") {
+		t.Errorf("mssql DDL must not use CREATE TABLE IF NOT EXISTS, got %q", ddl)
+	}
+}
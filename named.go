@@ -0,0 +1,82 @@
+package sqlquery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AndEqualNamed renders "`field`=:field", for use with NamedSelect/NamedGet
+// instead of the positional AndEqualString.
+func (o *SqlQueryHandler) AndEqualNamed(d Dialect, fieldName string) string {
+	if o.Error() {
+		return ""
+	}
+
+	return fmt.Sprintf("  AND %s=:%s", d.QuoteIdent(fieldName), fieldName)
+}
+
+// AndLikeNamed renders "`field` like :field".
+func (o *SqlQueryHandler) AndLikeNamed(d Dialect, fieldName string) string {
+	if o.Error() {
+		return ""
+	}
+
+	return fmt.Sprintf("  AND %s like :%s ", d.QuoteIdent(fieldName), fieldName)
+}
+
+// AndInNamed renders "`field` IN (:field)". The caller binds fieldName to a
+// slice; NamedSelect/NamedGet expand it via sqlx.In after sqlx.Named runs.
+func (o *SqlQueryHandler) AndInNamed(d Dialect, fieldName string) string {
+	if o.Error() {
+		return ""
+	}
+
+	return fmt.Sprintf("  AND %s IN (:%s) ", d.QuoteIdent(fieldName), fieldName)
+}
+
+// NamedSelect expands query's `:name` placeholders against arg (a struct or
+// map[string]interface{}), expands any slice-valued args for IN clauses,
+// rebinds for q's dialect, and runs the result through q.SelectContext.
+func (o *SqlQueryHandler) NamedSelect(ctx context.Context, q Queryable, dest interface{}, query string, arg interface{}) {
+	if o.Error() {
+		return
+	}
+
+	bound, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	bound, args, err = sqlx.In(bound, args...)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	o.Set(q.SelectContext(ctx, dest, q.Rebind(bound), args...))
+}
+
+// NamedGet is NamedSelect's single-row counterpart, backed by
+// Queryable.GetContext.
+func (o *SqlQueryHandler) NamedGet(ctx context.Context, q Queryable, dest interface{}, query string, arg interface{}) {
+	if o.Error() {
+		return
+	}
+
+	bound, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	bound, args, err = sqlx.In(bound, args...)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	o.Set(q.GetContext(ctx, dest, q.Rebind(bound), args...))
+}
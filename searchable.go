@@ -0,0 +1,15 @@
+package sqlquery
+
+// Field identifies a column by its table and column name, so predicate
+// builders can emit a properly qualified `table`.`field` reference.
+type Field struct {
+	Table string
+	Name  string
+}
+
+// Searchable maps a caller-facing criteria name (as used by AndEqual,
+// AndLike, and friends) to the Field it actually corresponds to, so callers
+// can expose stable search field names without leaking their schema.
+type Searchable interface {
+	CriteriaAlias(fieldName string) (Field, error)
+}
@@ -4,16 +4,111 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/hawkwithwind/gohandler"
 )
 
 type Database struct {
-	Conn *sqlx.DB
+	Conn    *sqlx.DB
+	Dialect Dialect
+	Hooks   []Hook
+
+	mu      sync.Mutex
+	cache   *stmtCache
+	txStmts map[*sqlx.Tx][]*sqlx.Stmt
+}
+
+// Prepared returns a cached, prepared statement for query, preparing and
+// caching it on first use. The cache is LRU-bounded; see StmtCacheStats for
+// hit/miss/eviction counts.
+func (db *Database) Prepared(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	if db.Conn == nil {
+		return nil, fmt.Errorf("db.Conn is null upon calling db.Prepared")
+	}
+
+	db.mu.Lock()
+	if db.cache == nil {
+		db.cache = newStmtCache(defaultStmtCacheCapacity)
+	}
+	cache := db.cache
+	db.mu.Unlock()
+
+	return cache.get(ctx, db.Conn, query)
+}
+
+// PreparedTx is Prepared's transaction-bound counterpart: it fetches (or
+// prepares) the cached statement for query and binds it to tx via
+// sqlx.Tx.StmtxContext. The bound statement is tracked so Commit/Rollback
+// can close it once tx ends.
+func (db *Database) PreparedTx(ctx context.Context, tx *sqlx.Tx, query string) (*sqlx.Stmt, error) {
+	stmt, err := db.Prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	txStmt := tx.StmtxContext(ctx, stmt)
+
+	db.mu.Lock()
+	if db.txStmts == nil {
+		db.txStmts = make(map[*sqlx.Tx][]*sqlx.Stmt)
+	}
+	db.txStmts[tx] = append(db.txStmts[tx], txStmt)
+	db.mu.Unlock()
+
+	return txStmt, nil
+}
+
+func (db *Database) preparedFor(ctx context.Context, tx *sqlx.Tx, query string) (*sqlx.Stmt, error) {
+	if tx != nil {
+		return db.PreparedTx(ctx, tx, query)
+	}
+	return db.Prepared(ctx, query)
+}
+
+// releaseTx closes and forgets every statement PreparedTx bound to tx, so
+// Commit/Rollback don't leak tx-scoped statements.
+func (db *Database) releaseTx(tx *sqlx.Tx) {
+	db.mu.Lock()
+	stmts := db.txStmts[tx]
+	delete(db.txStmts, tx)
+	db.mu.Unlock()
+
+	for _, stmt := range stmts {
+		stmt.Close()
+	}
+}
+
+// StmtCacheStats reports the prepared statement cache's hit/miss/eviction
+// counters.
+func (db *Database) StmtCacheStats() StmtCacheStats {
+	db.mu.Lock()
+	cache := db.cache
+	db.mu.Unlock()
+
+	if cache == nil {
+		return StmtCacheStats{}
+	}
+	return cache.snapshot()
+}
+
+// Close finalizes every cached prepared statement and closes the
+// underlying connection.
+func (db *Database) Close() error {
+	db.mu.Lock()
+	cache := db.cache
+	db.mu.Unlock()
+
+	if cache != nil {
+		cache.closeAll()
+	}
+
+	if db.Conn != nil {
+		return db.Conn.Close()
+	}
+	return nil
 }
 
 type SqlQueryHandler struct {
@@ -38,6 +133,7 @@ type Queryable interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Rebind(query string) string
 }
 
@@ -45,6 +141,11 @@ func (o *SqlQueryHandler) DefaultContext() (context.Context, context.CancelFunc)
 	return context.WithTimeout(context.Background(), 10*time.Second)
 }
 
+// Connect opens db.Conn and detects its Dialect from driverName. Once
+// connected, db's registered Hooks fire automatically around every query
+// run through SqlQueryHandler's SelectContext/QueryxContext/ExecContext
+// (which also use the prepared-statement cache) or through db.Queryable(tx)
+// directly — callers never have to choose between caching and hooks.
 func (o *SqlQueryHandler) Connect(db *Database, driverName string, dataSourceName string) {
 	if o.Error() {
 		return
@@ -53,6 +154,7 @@ func (o *SqlQueryHandler) Connect(db *Database, driverName string, dataSourceNam
 	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
 	var err error
 	db.Conn, err = sqlx.ConnectContext(ctx, driverName, dataSourceName)
+	db.Dialect = dialectForDriver(driverName)
 	o.Set(err)
 }
 
@@ -75,7 +177,7 @@ func (o *SqlQueryHandler) Begin(db *Database) *sqlx.Tx {
 	}
 }
 
-func (o *SqlQueryHandler) Rollback(tx *sqlx.Tx) {
+func (o *SqlQueryHandler) Rollback(db *Database, tx *sqlx.Tx) {
 	// wont check o.Err when rollback. always rollback.
 	// because rollback should be done after some error occurs.
 
@@ -84,6 +186,7 @@ func (o *SqlQueryHandler) Rollback(tx *sqlx.Tx) {
 		if err != nil {
 			o.Set(err)
 		}
+		db.releaseTx(tx)
 	} else {
 		if !o.Error() {
 			o.Set(fmt.Errorf("tx is null upon calling tx.Rollback"))
@@ -91,24 +194,25 @@ func (o *SqlQueryHandler) Rollback(tx *sqlx.Tx) {
 	}
 }
 
-func (o *SqlQueryHandler) Commit(tx *sqlx.Tx) {
+func (o *SqlQueryHandler) Commit(db *Database, tx *sqlx.Tx) {
 	if o.Error() {
 		return
 	}
 
 	if tx != nil {
 		o.Set(tx.Commit())
+		db.releaseTx(tx)
 	} else {
 		o.Set(fmt.Errorf("tx is null upon calling tx.Commit"))
 	}
 }
 
-func (o *SqlQueryHandler) CommitOrRollback(tx *sqlx.Tx) {
+func (o *SqlQueryHandler) CommitOrRollback(db *Database, tx *sqlx.Tx) {
 	if tx == nil && !o.Error() {
 		o.Set(fmt.Errorf("tx is null upon calling CommitOrRollback"))
 		return
 	}
-	
+
 	if o.Error() {
 		if tx != nil {
 			tx.Rollback()
@@ -116,135 +220,216 @@ func (o *SqlQueryHandler) CommitOrRollback(tx *sqlx.Tx) {
 	} else {
 		o.Set(tx.Commit())
 	}
+	db.releaseTx(tx)
+}
+
+// SelectContext runs query against db's (or, if tx is non-nil, tx's)
+// prepared statement cache instead of preparing it fresh on every call.
+// db's registered Hooks fire around the call same as db.Queryable does, so
+// callers get both caching and observability without choosing between them.
+func (o *SqlQueryHandler) SelectContext(ctx context.Context, db *Database, tx *sqlx.Tx, dest interface{}, query string, args ...interface{}) {
+	if o.Error() {
+		return
+	}
+
+	stmt, err := db.preparedFor(ctx, tx, query)
+	if err != nil {
+		o.Set(err)
+		return
+	}
+
+	ctx = runHooksBefore(db.Hooks, ctx, query, args)
+	start := time.Now()
+	err = stmt.SelectContext(ctx, dest, args...)
+	runHooksAfter(db.Hooks, ctx, query, args, err, start)
+	o.Set(err)
 }
 
-func (o *SqlQueryHandler) AndEqualString(fieldName string, field sql.NullString) string {
+// QueryxContext is SelectContext's cached-statement counterpart for
+// queries that scan into *sqlx.Rows instead of a destination slice.
+func (o *SqlQueryHandler) QueryxContext(ctx context.Context, db *Database, tx *sqlx.Tx, query string, args ...interface{}) *sqlx.Rows {
+	if o.Error() {
+		return nil
+	}
+
+	stmt, err := db.preparedFor(ctx, tx, query)
+	if err != nil {
+		o.Set(err)
+		return nil
+	}
+
+	ctx = runHooksBefore(db.Hooks, ctx, query, args)
+	start := time.Now()
+	rows, err := stmt.QueryxContext(ctx, args...)
+	runHooksAfter(db.Hooks, ctx, query, args, err, start)
+	o.Set(err)
+	return rows
+}
+
+// ExecContext is SelectContext's cached-statement counterpart for
+// statements that don't return rows.
+func (o *SqlQueryHandler) ExecContext(ctx context.Context, db *Database, tx *sqlx.Tx, query string, args ...interface{}) sql.Result {
+	if o.Error() {
+		return nil
+	}
+
+	stmt, err := db.preparedFor(ctx, tx, query)
+	if err != nil {
+		o.Set(err)
+		return nil
+	}
+
+	ctx = runHooksBefore(db.Hooks, ctx, query, args)
+	start := time.Now()
+	result, err := stmt.ExecContext(ctx, args...)
+	runHooksAfter(db.Hooks, withRowsAffected(ctx, result, err), query, args, err, start)
+	o.Set(err)
+	return result
+}
+
+func (o *SqlQueryHandler) AndEqualString(d Dialect, fieldName string, field sql.NullString) string {
 	if o.Error() {
 		return ""
 	}
 
+	ident := d.QuoteIdent(fieldName)
 	if field.Valid {
-		return fmt.Sprintf("  AND `%s`=?", fieldName)
+		return fmt.Sprintf("  AND %s=?", ident)
 	} else {
-		return fmt.Sprintf("  AND (1=1 OR `%s`=?)", fieldName)
+		return fmt.Sprintf("  AND (1=1 OR %s=?)", ident)
 	}
 }
 
-func (o *SqlQueryHandler) AndEqualStringT(tableName string, fieldName string, field sql.NullString) string {
+func (o *SqlQueryHandler) AndEqualStringT(d Dialect, tableName string, fieldName string, field sql.NullString) string {
 	if o.Error() {
 		return ""
 	}
 
+	ident := quoteTableField(d, tableName, fieldName)
 	if field.Valid {
-		return fmt.Sprintf("  AND `%s`,`%s`=?", tableName, fieldName)
+		return fmt.Sprintf("  AND %s=?", ident)
 	} else {
-		return fmt.Sprintf("  AND (1=1 OR `%s`.`%s`=?)", tableName, fieldName)
+		return fmt.Sprintf("  AND (1=1 OR %s=?)", ident)
 	}
 }
 
-func (o *SqlQueryHandler) AndLikeString(fieldName string, field sql.NullString) string {
+func (o *SqlQueryHandler) AndLikeString(d Dialect, fieldName string, field sql.NullString) string {
 	if o.Error() {
 		return ""
 	}
 
+	ident := d.QuoteIdent(fieldName)
 	if field.Valid {
-		return fmt.Sprintf("  AND `%s` like ? ", fieldName)
+		return fmt.Sprintf("  AND %s like ? ", ident)
 	} else {
-		return fmt.Sprintf("  AND (1=1 OR `%s`=?)", fieldName)
+		return fmt.Sprintf("  AND (1=1 OR %s=?)", ident)
 	}
 }
 
-func (o *SqlQueryHandler) AndLikeStringT(tableName string, fieldName string, field sql.NullString) string {
+func (o *SqlQueryHandler) AndLikeStringT(d Dialect, tableName string, fieldName string, field sql.NullString) string {
 	if o.Error() {
 		return ""
 	}
 
+	ident := quoteTableField(d, tableName, fieldName)
 	if field.Valid {
-		return fmt.Sprintf("  AND `%s`.`%s` like ? ", tableName, fieldName)
+		return fmt.Sprintf("  AND %s like ? ", ident)
 	} else {
-		return fmt.Sprintf("  AND (1=1 OR `%s`.`%s`=?)", tableName, fieldName)
+		return fmt.Sprintf("  AND (1=1 OR %s=?)", ident)
 	}
 }
 
-func (o *SqlQueryHandler) AndEqual(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndEqual(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf(" AND `%s`.`%s` = ?", fn.Table, fn.Name)
+	return fmt.Sprintf(" AND %s = ?", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndLike(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndLike(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf(" AND `%s`.`%s` like ?", fn.Table, fn.Name)
+	return fmt.Sprintf(" AND %s like ?", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndGreaterThan(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndGreaterThan(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf("  AND `%s`.`%s` > ? ", fn.Table, fn.Name)
+	return fmt.Sprintf("  AND %s > ? ", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndGreaterThanEqual(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndGreaterThanEqual(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf("  AND `%s`.`%s` >= ? ", fn.Table, fn.Name)
+	return fmt.Sprintf("  AND %s >= ? ", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndLessThan(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndLessThan(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf("  AND `%s`.`%s` < ? ", fn.Table, fn.Name)
+	return fmt.Sprintf("  AND %s < ? ", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndLessThanEqual(s Searchable, fieldName string, _ interface{}) string {
+func (o *SqlQueryHandler) AndLessThanEqual(d Dialect, s Searchable, fieldName string, _ interface{}) string {
 	if o.Error() {
 		return ""
 	}
 
 	var fn Field
-	fn, o.Err = s.CriteriaAlias(fieldName)
-	if o.Error() {
+	var err error
+	fn, err = s.CriteriaAlias(fieldName)
+	if err != nil {
+		o.Set(err)
 		return ""
 	}
-	return fmt.Sprintf("  AND `%s`.`%s` <= ? ", fn.Table, fn.Name)
+	return fmt.Sprintf("  AND %s <= ? ", quoteTableField(d, fn.Table, fn.Name))
 }
 
-func (o *SqlQueryHandler) AndIsIn(s Searchable, fieldName string, rhs interface{}) string {
+func (o *SqlQueryHandler) AndIsIn(d Dialect, s Searchable, fieldName string, rhs interface{}) string {
 	if o.Error() {
 		return ""
 	}
@@ -266,7 +451,7 @@ func (o *SqlQueryHandler) AndIsIn(s Searchable, fieldName string, rhs interface{
 			placeholders = append(placeholders, "?")
 		}
 
-		return fmt.Sprintf("  AND `%s`.`%s` IN (%s) ", fn.Table, fn.Name, strings.Join(placeholders, ","))
+		return fmt.Sprintf("  AND %s IN (%s) ", quoteTableField(d, fn.Table, fn.Name), strings.Join(placeholders, ","))
 	default:
 		o.Set(fmt.Errorf("where clause operator IN not support rhs type %T, should be list", rhs))
 		return ""
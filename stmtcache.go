@@ -0,0 +1,107 @@
+package sqlquery
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultStmtCacheCapacity bounds how many distinct queries Database will
+// keep prepared at once before evicting the least-recently-used one.
+const defaultStmtCacheCapacity = 256
+
+// StmtCacheStats is a point-in-time snapshot of a Database's prepared
+// statement cache counters.
+type StmtCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// stmtCache is an LRU-bounded cache of *sqlx.Stmt keyed by query text.
+type stmtCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	stmts    map[string]*sqlx.Stmt
+	stats    StmtCacheStats
+}
+
+func newStmtCache(capacity int) *stmtCache {
+	return &stmtCache{capacity: capacity, stmts: make(map[string]*sqlx.Stmt)}
+}
+
+func (c *stmtCache) get(ctx context.Context, conn *sqlx.DB, query string) (*sqlx.Stmt, error) {
+	c.mu.Lock()
+	if stmt, ok := c.stmts[query]; ok {
+		c.touch(query)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return stmt, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	stmt, err := conn.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have prepared and cached the same query while we
+	// were outside the lock; keep theirs and drop ours.
+	if existing, ok := c.stmts[query]; ok {
+		stmt.Close()
+		c.touch(query)
+		return existing, nil
+	}
+
+	c.evictIfFull()
+	c.stmts[query] = stmt
+	c.order = append(c.order, query)
+	return stmt, nil
+}
+
+func (c *stmtCache) touch(query string) {
+	for i, q := range c.order {
+		if q == query {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, query)
+}
+
+func (c *stmtCache) evictIfFull() {
+	if c.capacity <= 0 || len(c.order) < c.capacity {
+		return
+	}
+
+	oldest := c.order[0]
+	c.order = c.order[1:]
+	if stmt, ok := c.stmts[oldest]; ok {
+		stmt.Close()
+		delete(c.stmts, oldest)
+	}
+	c.stats.Evictions++
+}
+
+func (c *stmtCache) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, stmt := range c.stmts {
+		stmt.Close()
+	}
+	c.stmts = make(map[string]*sqlx.Stmt)
+	c.order = nil
+}
+
+func (c *stmtCache) snapshot() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
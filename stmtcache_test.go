@@ -0,0 +1,55 @@
+package sqlquery
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStmtCacheHitsAndMisses(t *testing.T) {
+	db, _ := newFakeDB(t)
+	c := newStmtCache(defaultStmtCacheCapacity)
+	ctx := context.Background()
+
+	if _, err := c.get(ctx, db, "SELECT 1"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := c.get(ctx, db, "SELECT 1"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	stats := c.snapshot()
+	if stats.Misses != 1 || stats.Hits != 1 {
+		t.Errorf("got %+v, want 1 miss and 1 hit", stats)
+	}
+}
+
+func TestStmtCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	db, _ := newFakeDB(t)
+	c := newStmtCache(2)
+	ctx := context.Background()
+
+	mustGet := func(query string) {
+		t.Helper()
+		if _, err := c.get(ctx, db, query); err != nil {
+			t.Fatalf("get(%q): %v", query, err)
+		}
+	}
+
+	mustGet("SELECT 1")
+	mustGet("SELECT 2")
+	mustGet("SELECT 1") // touch 1, so 2 becomes the least recently used
+	mustGet("SELECT 3") // over capacity: should evict 2, not 1
+
+	if stats := c.snapshot(); stats.Evictions != 1 {
+		t.Errorf("got %d evictions, want 1", stats.Evictions)
+	}
+	if _, ok := c.stmts["SELECT 2"]; ok {
+		t.Errorf("SELECT 2 should have been evicted")
+	}
+	if _, ok := c.stmts["SELECT 1"]; !ok {
+		t.Errorf("SELECT 1 should still be cached")
+	}
+	if _, ok := c.stmts["SELECT 3"]; !ok {
+		t.Errorf("SELECT 3 should be cached")
+	}
+}